@@ -0,0 +1,134 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// defaultCacheDir returns ~/.cache/cec2020, the fallback used when
+// -cache-dir isn't given.
+func defaultCacheDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".cache/cec2020"
+	}
+	return filepath.Join(home, ".cache", "cec2020")
+}
+
+// Cache is a content-addressed, on-disk store of fetched pages, keyed
+// by the sha256 of the request URL. It lets a crawl resume after a
+// failure or Ctrl-C without re-fetching pages it already has.
+type Cache struct {
+	dir     string
+	disable bool
+}
+
+func NewCache(dir string, disable bool) *Cache {
+	return &Cache{dir: dir, disable: disable}
+}
+
+func (c *Cache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".html")
+}
+
+// Get returns the cached body for url, if present and caching isn't
+// disabled via -refresh.
+func (c *Cache) Get(url string) ([]byte, bool) {
+	if c.disable {
+		return nil, false
+	}
+	body, err := ioutil.ReadFile(c.path(url))
+	if err != nil {
+		return nil, false
+	}
+	return body, true
+}
+
+// Put writes body to the cache for url, creating the cache directory
+// if needed.
+func (c *Cache) Put(url string, body []byte) {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		log.Printf("error: caching %s: %v\n", url, err)
+		return
+	}
+	if err := ioutil.WriteFile(c.path(url), body, 0o644); err != nil {
+		log.Printf("error: caching %s: %v\n", url, err)
+	}
+}
+
+// Checkpoint tracks which Area.Ids have been fully processed, so a
+// restarted crawl can skip them and only reuse cached HTML for the
+// rest.
+type Checkpoint struct {
+	path string
+
+	mu   sync.Mutex
+	done map[string]bool
+}
+
+// NewCheckpoint loads a checkpoint from path, unless reset is true (as
+// with -refresh), in which case it starts empty so a fresh crawl isn't
+// blocked by a previous run's progress.
+func NewCheckpoint(path string, reset bool) *Checkpoint {
+	cp := &Checkpoint{path: path, done: make(map[string]bool)}
+	if !reset {
+		cp.load()
+	}
+	return cp
+}
+
+func (cp *Checkpoint) load() {
+	data, err := ioutil.ReadFile(cp.path)
+	if err != nil {
+		return
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return
+	}
+	for _, id := range ids {
+		cp.done[id] = true
+	}
+}
+
+func (cp *Checkpoint) Done(id string) bool {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.done[id]
+}
+
+// MarkDone records id as fully processed and persists the checkpoint
+// file immediately, so progress survives a crash mid-crawl.
+func (cp *Checkpoint) MarkDone(id string) {
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.done[id] = true
+
+	ids := make([]string, 0, len(cp.done))
+	for id := range cp.done {
+		ids = append(ids, id)
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		log.Printf("error: %v\n", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(cp.path), 0o755); err != nil {
+		log.Printf("error: %v\n", err)
+		return
+	}
+	if err := ioutil.WriteFile(cp.path, data, 0o644); err != nil {
+		log.Printf("error: %v\n", err)
+	}
+}
+
+func checkpointPath(cacheDir string) string {
+	return filepath.Join(cacheDir, "checkpoint.json")
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	c := NewCache(t.TempDir(), false)
+
+	if _, ok := c.Get("http://example.com"); ok {
+		t.Fatal("Get on empty cache returned ok=true")
+	}
+
+	c.Put("http://example.com", []byte("body"))
+
+	body, ok := c.Get("http://example.com")
+	if !ok {
+		t.Fatal("Get after Put returned ok=false")
+	}
+	if string(body) != "body" {
+		t.Errorf("Get = %q, want %q", body, "body")
+	}
+}
+
+func TestCacheDisabled(t *testing.T) {
+	c := NewCache(t.TempDir(), true)
+	c.Put("http://example.com", []byte("body"))
+
+	if _, ok := c.Get("http://example.com"); ok {
+		t.Fatal("Get on disabled cache returned ok=true")
+	}
+}
+
+func TestCheckpointMarkDoneAndReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	cp := NewCheckpoint(path, false)
+	if cp.Done("area-1") {
+		t.Fatal("Done on empty checkpoint returned true")
+	}
+
+	cp.MarkDone("area-1")
+	if !cp.Done("area-1") {
+		t.Fatal("Done after MarkDone returned false")
+	}
+
+	reloaded := NewCheckpoint(path, false)
+	if !reloaded.Done("area-1") {
+		t.Fatal("reloaded checkpoint lost a MarkDone'd id")
+	}
+}
+
+func TestCheckpointReset(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	cp := NewCheckpoint(path, false)
+	cp.MarkDone("area-1")
+
+	reset := NewCheckpoint(path, true)
+	if reset.Done("area-1") {
+		t.Fatal("NewCheckpoint with reset=true loaded prior progress")
+	}
+}
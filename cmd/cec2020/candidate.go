@@ -0,0 +1,43 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Candidate identifies one ticket on the ballot: the presidential
+// candidate, their running mate, and the nominating party.
+type Candidate struct {
+	Name        string
+	RunningMate string
+	Party       string
+}
+
+var brTag = regexp.MustCompile(`(?i)<br\s*/?>`)
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// parseCandidate turns the raw `<td>` cell HTML (candidate name and
+// running mate name separated by `<br/>`) into a Candidate, looking up
+// the party affiliation by ballot number in partyByNumber (a Site's
+// own table — ballot numbering and party lineup differ per election).
+func parseCandidate(number, cellHTML string, partyByNumber map[string]string) Candidate {
+	text := brTag.ReplaceAllString(cellHTML, "\n")
+	text = htmlTag.ReplaceAllString(text, "")
+
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	candidate := Candidate{Party: partyByNumber[number]}
+	if len(lines) > 0 {
+		candidate.Name = lines[0]
+	}
+	if len(lines) > 1 {
+		candidate.RunningMate = lines[1]
+	}
+	return candidate
+}
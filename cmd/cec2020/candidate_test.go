@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCandidate(t *testing.T) {
+	parties := map[string]string{"1": "親民黨", "2": "民進黨", "3": "國民黨"}
+
+	tests := []struct {
+		name     string
+		number   string
+		cellHTML string
+		want     Candidate
+	}{
+		{
+			name:     "name and running mate",
+			number:   "2",
+			cellHTML: "蔡英文<br/>賴清德",
+			want:     Candidate{Name: "蔡英文", RunningMate: "賴清德", Party: "民進黨"},
+		},
+		{
+			name:     "uppercase self-closing br with spaces",
+			number:   "3",
+			cellHTML: "韓國瑜<BR />張善政",
+			want:     Candidate{Name: "韓國瑜", RunningMate: "張善政", Party: "國民黨"},
+		},
+		{
+			name:     "name only, no running mate",
+			number:   "1",
+			cellHTML: "宋楚瑜",
+			want:     Candidate{Name: "宋楚瑜", Party: "親民黨"},
+		},
+		{
+			name:     "unknown ballot number has no party",
+			number:   "9",
+			cellHTML: "某人<br/>某副手",
+			want:     Candidate{Name: "某人", RunningMate: "某副手"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseCandidate(tt.number, tt.cellHTML, parties)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCandidate(%q, %q) = %+v, want %+v", tt.number, tt.cellHTML, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
+)
+
+const (
+	maxRetries     = 5
+	baseBackoff    = 500 * time.Millisecond
+	maxBackoff     = 30 * time.Second
+	requestTimeout = 15 * time.Second
+)
+
+// httpClient is shared by getDocument/getResponseBody so a single hung
+// request can't stall the whole crawl.
+var httpClient = &http.Client{
+	Timeout: requestTimeout,
+}
+
+// limiter caps how many requests/second the worker pool sends to CEC,
+// regardless of how many workers are running concurrently.
+var limiter = rate.NewLimiter(rate.Limit(10), 1)
+
+// cache is consulted by getResponseBody/getDocument before hitting the
+// network. main() replaces it once -cache-dir/-refresh are parsed.
+var cache = NewCache(defaultCacheDir(), false)
+
+// checkpoint records which Area.Ids have been fully processed. main()
+// replaces it once -cache-dir is parsed.
+var checkpoint = NewCheckpoint(checkpointPath(defaultCacheDir()), false)
+
+// fetch performs an HTTP GET with retries, exponential backoff plus
+// jitter, and Retry-After handling on 429/503. It blocks on the shared
+// rate limiter before every attempt.
+func fetch(url string) (*http.Response, error) {
+	var lastErr error
+
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			delay := backoffDelay(attempt)
+			time.Sleep(delay)
+		}
+
+		if err := limiter.Wait(context.Background()); err != nil {
+			return nil, err
+		}
+
+		resp, err := httpClient.Get(url)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+			retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"))
+			resp.Body.Close()
+			lastErr = fmt.Errorf("fetch %s: status %d", url, resp.StatusCode)
+			if retryAfter > 0 {
+				time.Sleep(retryAfter)
+			}
+			continue
+		}
+
+		if resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = fmt.Errorf("fetch %s: status %d", url, resp.StatusCode)
+			continue
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("fetch %s: giving up after %d attempts: %w", url, maxRetries+1, lastErr)
+}
+
+// backoffDelay returns an exponential backoff duration for the given
+// attempt number, with up to 20% jitter added to avoid thundering-herd
+// retries across the worker pool.
+func backoffDelay(attempt int) time.Duration {
+	delay := baseBackoff * time.Duration(1<<uint(attempt-1))
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) / 5))
+	return delay + jitter
+}
+
+// parseRetryAfter interprets a Retry-After header given in seconds.
+// CEC doesn't send HTTP-date values in practice, so that form isn't
+// supported.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(header)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func getResponseBody(url string) ([]byte, error) {
+	if body, ok := cache.Get(url); ok {
+		return body, nil
+	}
+
+	resp, err := fetch(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	cache.Put(url, body)
+	return body, nil
+}
+
+func getDocument(url string) (*goquery.Document, error) {
+	body, err := getResponseBody(url)
+	if err != nil {
+		return nil, err
+	}
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffDelay(t *testing.T) {
+	for attempt := 1; attempt <= 8; attempt++ {
+		delay := backoffDelay(attempt)
+		if delay < baseBackoff {
+			t.Errorf("attempt %d: delay %v below baseBackoff %v", attempt, delay, baseBackoff)
+		}
+		// Jitter is bounded (delay/5 before capping), but the capped
+		// base alone can already sit at maxBackoff, so allow the cap
+		// plus its own jitter band as the ceiling.
+		if delay > maxBackoff+maxBackoff/5 {
+			t.Errorf("attempt %d: delay %v exceeds maxBackoff+jitter %v", attempt, delay, maxBackoff+maxBackoff/5)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"not-a-number", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+type Area struct {
+	Id       string
+	Name     string
+	Division string
+}
+
+type Result struct {
+	Division   string
+	District   string
+	Number     string
+	Candidate  Candidate
+	Ballots    int64
+	Percentage float64
+}
+
+type Worker struct {
+	sync.WaitGroup
+	site     *Site
+	jobCh    chan *Area
+	resultCh chan *Result
+}
+
+func NewWorker(n int, site *Site) *Worker {
+	worker := &Worker{
+		site:     site,
+		jobCh:    make(chan *Area),
+		resultCh: make(chan *Result),
+	}
+	for i := 0; i < n; i++ {
+		go worker.worker()
+	}
+	return worker
+}
+
+func (w *Worker) Add(area *Area) {
+	w.WaitGroup.Add(1)
+	w.jobCh <- area
+}
+
+func (w *Worker) Wait() {
+	w.WaitGroup.Wait()
+	close(w.resultCh)
+}
+
+func (w *Worker) Result() <-chan *Result {
+	return w.resultCh
+}
+
+func (w *Worker) worker() {
+	for j := range w.jobCh {
+		w.doJob(j)
+	}
+}
+
+func (w *Worker) doJob(area *Area) {
+	defer w.WaitGroup.Done()
+
+	doc, err := getDocument(w.site.AreaURL(area.Id))
+	if err != nil {
+		log.Printf("error: %v\n", err)
+		return
+	}
+
+	ok := true
+	doc.Find(w.site.RowSelector).Each(func(i int, row *goquery.Selection) {
+		record, err := w.site.Extract(row)
+		if err != nil {
+			log.Printf("error: extracting row: %v\n", err)
+			ok = false
+			return
+		}
+
+		result, err := buildResult(area, record, w.site.PartyByNumber)
+		if err != nil {
+			log.Printf("error: building result: %v\n", err)
+			ok = false
+			return
+		}
+
+		w.resultCh <- result
+	})
+
+	if ok {
+		checkpoint.MarkDone(area.Id)
+	}
+}
+
+// buildResult turns a Site's extracted field record into a Result,
+// reading it by role ("number", "candidate", "ballots", "percentage")
+// rather than assuming particular field names — Site.validateRoles
+// guarantees any loaded config declares all four, however it names or
+// arranges its own fields. Party affiliation comes from the Site's own
+// partyByNumber table, since ballot numbering and the party lineup
+// differ per election.
+func buildResult(area *Area, record map[string]string, partyByNumber map[string]string) (*Result, error) {
+	result := &Result{
+		Division: area.Division,
+		District: area.Name,
+		Number:   record["number"],
+	}
+
+	result.Candidate = parseCandidate(result.Number, record["candidate"], partyByNumber)
+
+	ballots := strings.ReplaceAll(record["ballots"], ",", "")
+	ballotsValue, err := strconv.ParseInt(ballots, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	result.Ballots = ballotsValue
+
+	percentage := strings.TrimSuffix(strings.TrimSpace(record["percentage"]), "%")
+	percentageValue, err := strconv.ParseFloat(percentage, 64)
+	if err != nil {
+		return nil, err
+	}
+	result.Percentage = percentageValue
+
+	return result, nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "summary" {
+		if err := runSummary(os.Args[2:]); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+		return
+	}
+
+	format := flag.String("format", "csv", "output format: csv, json, ndjson, or sqlite")
+	output := flag.String("output", "", "output path (default: stdout; required for sqlite)")
+	cacheDir := flag.String("cache-dir", defaultCacheDir(), "directory for cached pages and the checkpoint file")
+	refresh := flag.Bool("refresh", false, "bypass the on-disk cache and re-fetch every page")
+	siteName := flag.String("site", "2020-president", "built-in site config to scrape: 2020-president or 2024-president")
+	configPath := flag.String("config", "", "path to a custom Site YAML config, overriding -site (see cmd/sites/examples for starting points)")
+	flag.Parse()
+
+	cache = NewCache(*cacheDir, *refresh)
+	checkpoint = NewCheckpoint(checkpointPath(*cacheDir), *refresh)
+
+	site, err := loadSiteFlag(*configPath, *siteName)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	sink, err := NewSink(*format, *output)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	table, err := getFolderStructure(site)
+	if err != nil {
+		log.Fatalf("error: %v", err)
+	}
+
+	w := NewWorker(30, site)
+	ch := w.Result()
+	go addJobs(w, table)
+
+	for res := range ch {
+		if err := sink.Write(res); err != nil {
+			log.Fatalf("error writing result: %v", err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		log.Fatalf("error closing sink: %v", err)
+	}
+}
+
+// loadSiteFlag resolves the Site to scrape: a custom -config file takes
+// priority over the built-in -site name.
+func loadSiteFlag(configPath, siteName string) (*Site, error) {
+	if configPath != "" {
+		return LoadSite(configPath)
+	}
+	return BuiltinSite(siteName)
+}
+
+// openOutput returns stdout (wrapped so Close is a no-op) when path is
+// empty, otherwise creates the file at path.
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "" {
+		return nopCloser{os.Stdout}, nil
+	}
+	return os.Create(path)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
+
+func addJobs(w *Worker, table map[int]map[int]*Area) {
+	for _, row := range table {
+		for j, area := range row {
+			if j != 0 && !checkpoint.Done(area.Id) {
+				w.Add(area)
+			}
+		}
+	}
+	w.Wait()
+}
+
+func getFolderStructure(site *Site) (map[int]map[int]*Area, error) {
+	body, err := getResponseBody(site.IndexURL)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int]map[int]*Area)
+
+	idPattern := regexp.MustCompile(site.IDPattern)
+	for _, group := range idPattern.FindAllSubmatch(body, -1) {
+		i, _ := strconv.Atoi(string(group[1]))
+		j, _ := strconv.Atoi(string(group[2]))
+		if j == 0 {
+			result[i] = make(map[int]*Area)
+		}
+		result[i][j] = &Area{Id: string(group[3])}
+	}
+
+	namePattern := regexp.MustCompile(site.NamePattern)
+	for _, group := range namePattern.FindAllSubmatch(body, -1) {
+		i, _ := strconv.Atoi(string(group[1]))
+		j, _ := strconv.Atoi(string(group[2]))
+		if j != 0 {
+			result[i][j].Division = result[i][0].Name
+		}
+		result[i][j].Name = string(group[3])
+	}
+	return result, nil
+}
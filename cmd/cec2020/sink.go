@@ -0,0 +1,210 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	_ "modernc.org/sqlite"
+)
+
+// Sink receives scraped results as they arrive and persists them in some
+// format. Implementations must be safe to call Write on repeatedly and
+// must flush/finalize everything in Close.
+type Sink interface {
+	Write(*Result) error
+	Close() error
+}
+
+// NewSink constructs the Sink for the given -format and -output path.
+// sqlite opens its own database handle on path directly; the other
+// formats open path as a plain file (or stdout, if path is empty).
+func NewSink(format, path string) (Sink, error) {
+	if format == "sqlite" {
+		return newSQLiteSink(path)
+	}
+
+	w, err := openOutput(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "csv", "":
+		return newCSVSink(w), nil
+	case "json":
+		return newJSONSink(w), nil
+	case "ndjson":
+		return newNDJSONSink(w), nil
+	default:
+		w.Close()
+		return nil, fmt.Errorf("unknown sink format %q", format)
+	}
+}
+
+var csvHeader = []string{"縣市", "鄉鎮市區", "號次", "總統", "副總統", "政黨", "得票數", "得票率%"}
+
+func resultToRow(res *Result) []string {
+	return []string{
+		res.Division,
+		res.District,
+		res.Number,
+		res.Candidate.Name,
+		res.Candidate.RunningMate,
+		res.Candidate.Party,
+		strconv.FormatInt(res.Ballots, 10),
+		strconv.FormatFloat(res.Percentage, 'f', -1, 64),
+	}
+}
+
+type csvSink struct {
+	out *csv.Writer
+	w   io.WriteCloser
+}
+
+func newCSVSink(w io.WriteCloser) *csvSink {
+	s := &csvSink{out: csv.NewWriter(w), w: w}
+	s.out.Write(csvHeader)
+	return s
+}
+
+func (s *csvSink) Write(res *Result) error {
+	if err := s.out.Write(resultToRow(res)); err != nil {
+		return err
+	}
+	s.out.Flush()
+	return s.out.Error()
+}
+
+func (s *csvSink) Close() error {
+	return s.w.Close()
+}
+
+// jsonSink streams a JSON array, writing each result as it arrives
+// instead of buffering the whole result set in memory.
+type jsonSink struct {
+	w       io.WriteCloser
+	enc     *json.Encoder
+	started bool
+}
+
+func newJSONSink(w io.WriteCloser) *jsonSink {
+	return &jsonSink{w: w, enc: json.NewEncoder(w)}
+}
+
+func (s *jsonSink) Write(res *Result) error {
+	if !s.started {
+		if _, err := s.w.Write([]byte("[\n")); err != nil {
+			return err
+		}
+		s.started = true
+	} else {
+		if _, err := s.w.Write([]byte(",\n")); err != nil {
+			return err
+		}
+	}
+	return s.enc.Encode(res)
+}
+
+func (s *jsonSink) Close() error {
+	if !s.started {
+		if _, err := s.w.Write([]byte("[]\n")); err != nil {
+			return err
+		}
+	} else if _, err := s.w.Write([]byte("]\n")); err != nil {
+		return err
+	}
+	return s.w.Close()
+}
+
+// ndjsonSink writes one JSON object per line (newline-delimited JSON),
+// streamed straight from resultCh with no buffering.
+type ndjsonSink struct {
+	w   io.WriteCloser
+	enc *json.Encoder
+}
+
+func newNDJSONSink(w io.WriteCloser) *ndjsonSink {
+	return &ndjsonSink{w: w, enc: json.NewEncoder(w)}
+}
+
+func (s *ndjsonSink) Write(res *Result) error {
+	return s.enc.Encode(res)
+}
+
+func (s *ndjsonSink) Close() error {
+	return s.w.Close()
+}
+
+// sqliteSink inserts each result into a results table inside a single
+// transaction, committed on Close.
+type sqliteSink struct {
+	db   *sql.DB
+	tx   *sql.Tx
+	stmt *sql.Stmt
+}
+
+func newSQLiteSink(path string) (*sqliteSink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("sqlite sink requires -output <path>")
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS results (
+		division     TEXT,
+		district     TEXT,
+		number       TEXT,
+		name         TEXT,
+		running_mate TEXT,
+		party        TEXT,
+		ballots      INTEGER,
+		percentage   REAL
+	)`); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO results (division, district, number, name, running_mate, party, ballots, percentage)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		db.Close()
+		return nil, err
+	}
+
+	return &sqliteSink{db: db, tx: tx, stmt: stmt}, nil
+}
+
+func (s *sqliteSink) Write(res *Result) error {
+	_, err := s.stmt.Exec(
+		res.Division, res.District, res.Number,
+		res.Candidate.Name, res.Candidate.RunningMate, res.Candidate.Party,
+		res.Ballots, res.Percentage,
+	)
+	return err
+}
+
+func (s *sqliteSink) Close() error {
+	if err := s.stmt.Close(); err != nil {
+		s.db.Close()
+		return err
+	}
+	if err := s.tx.Commit(); err != nil {
+		s.db.Close()
+		return err
+	}
+	return s.db.Close()
+}
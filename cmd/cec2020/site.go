@@ -0,0 +1,155 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"gopkg.in/yaml.v3"
+)
+
+// Only sites/*.yaml is embedded, not sites/examples/*.yaml: those are
+// unverified starting points for races we haven't confirmed against a
+// live CEC page, and aren't exposed as ready-to-use built-ins.
+//
+//go:embed sites/*.yaml
+var builtinSiteFiles embed.FS
+
+// FieldExtractor describes how to pull one named field out of a result
+// row: which `<td>` to read and how to turn it into text. Role tags the
+// field as playing one of the roles buildResult assembles a Result
+// from ("number", "candidate", "ballots", "percentage"); Name is just a
+// human-readable label and doesn't have to match it.
+type FieldExtractor struct {
+	Name      string `yaml:"name"`
+	Role      string `yaml:"role"`
+	CellIndex int    `yaml:"cellIndex"`
+	Transform string `yaml:"transform"`
+	Regex     string `yaml:"regex,omitempty"`
+}
+
+// resultRoles are the Roles buildResult requires a Site's fields to
+// cover between them; every built-in and custom config is validated
+// against this list when loaded, so a config with a typo'd or missing
+// role fails fast instead of silently producing blank Result fields.
+var resultRoles = []string{"number", "candidate", "ballots", "percentage"}
+
+// Extract reads this field out of a row's cells according to Transform:
+// "text" (default), "html", "stripCommas", or "regex" (first capture
+// group of Regex against the cell text).
+func (f FieldExtractor) Extract(cells *goquery.Selection) (string, error) {
+	cell := cells.Eq(f.CellIndex)
+
+	switch f.Transform {
+	case "", "text":
+		return strings.TrimSpace(cell.Text()), nil
+	case "html":
+		html, err := cell.Html()
+		return html, err
+	case "stripCommas":
+		return strings.ReplaceAll(cell.Text(), ",", ""), nil
+	case "regex":
+		re, err := regexp.Compile(f.Regex)
+		if err != nil {
+			return "", fmt.Errorf("field %q: %w", f.Name, err)
+		}
+		match := re.FindStringSubmatch(cell.Text())
+		if len(match) < 2 {
+			return "", nil
+		}
+		return match[1], nil
+	default:
+		return "", fmt.Errorf("field %q: unknown transform %q", f.Name, f.Transform)
+	}
+}
+
+// Site describes everything the crawler needs to scrape one CEC
+// election: where the area index lives, how to parse it, the per-area
+// result page URL, the row selector, and the fields to extract from
+// each row.
+type Site struct {
+	Name            string            `yaml:"name"`
+	IndexURL        string            `yaml:"indexURL"`
+	IDPattern       string            `yaml:"idPattern"`
+	NamePattern     string            `yaml:"namePattern"`
+	AreaURLTemplate string            `yaml:"areaURLTemplate"`
+	RowSelector     string            `yaml:"rowSelector"`
+	Fields          []FieldExtractor  `yaml:"fields"`
+	PartyByNumber   map[string]string `yaml:"partyByNumber,omitempty"`
+}
+
+// LoadSite reads a Site config from a YAML file on disk, for -config.
+func LoadSite(path string) (*Site, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseSite(data)
+}
+
+func parseSite(data []byte) (*Site, error) {
+	var site Site
+	if err := yaml.Unmarshal(data, &site); err != nil {
+		return nil, err
+	}
+	if err := site.validateRoles(); err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+// validateRoles checks that every role buildResult relies on is
+// declared by exactly one of the site's fields.
+func (s *Site) validateRoles() error {
+	seen := make(map[string]bool, len(s.Fields))
+	for _, field := range s.Fields {
+		if field.Role == "" {
+			continue
+		}
+		if seen[field.Role] {
+			return fmt.Errorf("site %q: role %q declared by more than one field", s.Name, field.Role)
+		}
+		seen[field.Role] = true
+	}
+	for _, role := range resultRoles {
+		if !seen[role] {
+			return fmt.Errorf("site %q: no field declares role %q", s.Name, role)
+		}
+	}
+	return nil
+}
+
+// BuiltinSite looks up one of the verified configs shipped directly
+// under cmd/sites (not cmd/sites/examples) by name, its filename
+// without the .yaml extension: "2020-president" or "2024-president".
+func BuiltinSite(name string) (*Site, error) {
+	data, err := builtinSiteFiles.ReadFile("sites/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown built-in site %q", name)
+	}
+	return parseSite(data)
+}
+
+// AreaURL fills the site's area URL template with an Area.Id.
+func (s *Site) AreaURL(areaID string) string {
+	return fmt.Sprintf(s.AreaURLTemplate, areaID)
+}
+
+// Extract runs every field extractor over a row's cells, returning a
+// role -> value record (buildResult reads this by role, e.g.
+// record["ballots"], regardless of how the field is named in config).
+func (s *Site) Extract(row *goquery.Selection) (map[string]string, error) {
+	cells := row.Find("td")
+	record := make(map[string]string, len(s.Fields))
+	for _, field := range s.Fields {
+		value, err := field.Extract(cells)
+		if err != nil {
+			return nil, err
+		}
+		record[field.Role] = value
+	}
+	return record, nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func testRowCells(t *testing.T, rowHTML string) *goquery.Selection {
+	t.Helper()
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader("<table><tr>" + rowHTML + "</tr></table>"))
+	if err != nil {
+		t.Fatalf("parsing test row: %v", err)
+	}
+	return doc.Find("tr").Find("td")
+}
+
+func TestFieldExtractorExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   FieldExtractor
+		row     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "text trims whitespace",
+			field: FieldExtractor{Name: "number", CellIndex: 0, Transform: "text"},
+			row:   "<td>  1  </td>",
+			want:  "1",
+		},
+		{
+			name:  "default transform is text",
+			field: FieldExtractor{Name: "number", CellIndex: 0},
+			row:   "<td> 1 </td>",
+			want:  "1",
+		},
+		{
+			name:  "html keeps markup",
+			field: FieldExtractor{Name: "candidate", CellIndex: 0, Transform: "html"},
+			row:   "<td>蔡英文<br/>賴清德</td>",
+			want:  "蔡英文<br/>賴清德",
+		},
+		{
+			name:  "stripCommas removes thousands separators",
+			field: FieldExtractor{Name: "ballots", CellIndex: 1, Transform: "stripCommas"},
+			row:   "<td>1</td><td>1,234,567</td>",
+			want:  "1234567",
+		},
+		{
+			name:  "regex returns first capture group",
+			field: FieldExtractor{Name: "percentage", CellIndex: 0, Transform: "regex", Regex: `([\d.]+)%`},
+			row:   "<td>38.61%</td>",
+			want:  "38.61",
+		},
+		{
+			name:  "regex with no match returns empty string",
+			field: FieldExtractor{Name: "percentage", CellIndex: 0, Transform: "regex", Regex: `([\d.]+)%`},
+			row:   "<td>n/a</td>",
+			want:  "",
+		},
+		{
+			name:    "unknown transform is an error",
+			field:   FieldExtractor{Name: "number", CellIndex: 0, Transform: "upper"},
+			row:     "<td>1</td>",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.field.Extract(testRowCells(t, tt.row))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Extract() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Extract() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSiteExtract(t *testing.T) {
+	site := &Site{
+		Fields: []FieldExtractor{
+			{Name: "number", Role: "number", CellIndex: 0, Transform: "text"},
+			{Name: "ballots", Role: "ballots", CellIndex: 1, Transform: "stripCommas"},
+		},
+	}
+
+	row := testRowCells(t, "<td>1</td><td>1,234</td>").Closest("tr")
+	record, err := site.Extract(row)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+
+	want := map[string]string{"number": "1", "ballots": "1234"}
+	if len(record) != len(want) || record["number"] != want["number"] || record["ballots"] != want["ballots"] {
+		t.Errorf("Extract() = %+v, want %+v", record, want)
+	}
+}
+
+func TestParseSiteValidatesRoles(t *testing.T) {
+	complete := `
+name: test
+areaURLTemplate: "%s"
+rowSelector: .trT
+fields:
+  - name: n
+    role: number
+    cellIndex: 0
+  - name: c
+    role: candidate
+    cellIndex: 1
+  - name: b
+    role: ballots
+    cellIndex: 2
+  - name: p
+    role: percentage
+    cellIndex: 3
+`
+	if _, err := parseSite([]byte(complete)); err != nil {
+		t.Errorf("parseSite with all roles declared: %v", err)
+	}
+
+	missing := `
+name: test
+fields:
+  - name: n
+    role: number
+    cellIndex: 0
+`
+	if _, err := parseSite([]byte(missing)); err == nil {
+		t.Error("parseSite with missing roles: want error, got nil")
+	}
+
+	duplicate := `
+name: test
+fields:
+  - name: n
+    role: number
+    cellIndex: 0
+  - name: n2
+    role: number
+    cellIndex: 1
+  - name: c
+    role: candidate
+    cellIndex: 2
+  - name: b
+    role: ballots
+    cellIndex: 3
+  - name: p
+    role: percentage
+    cellIndex: 4
+`
+	if _, err := parseSite([]byte(duplicate)); err == nil {
+		t.Error("parseSite with duplicate role: want error, got nil")
+	}
+}
+
+func TestBuiltinSitesDeclareAllRoles(t *testing.T) {
+	for _, name := range []string{"2020-president", "2024-president"} {
+		if _, err := BuiltinSite(name); err != nil {
+			t.Errorf("BuiltinSite(%q): %v", name, err)
+		}
+	}
+}
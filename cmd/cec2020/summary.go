@@ -0,0 +1,355 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	_ "modernc.org/sqlite"
+)
+
+// SummaryRow is one line of aggregated output: either a candidate's
+// totals within a single Division (scope == "division") or their
+// totals across the whole country (scope == "national").
+type SummaryRow struct {
+	Scope       string
+	Division    string
+	Number      string
+	Name        string
+	RunningMate string
+	Party       string
+	Ballots     int64
+	Share       float64
+	Winner      bool
+	Margin      int64
+}
+
+var summaryHeader = []string{
+	"scope", "縣市", "號次", "總統", "副總統", "政黨", "得票數", "得票率%", "當選", "領先票數",
+}
+
+func summaryRowToRecord(r SummaryRow) []string {
+	return []string{
+		r.Scope,
+		r.Division,
+		r.Number,
+		r.Name,
+		r.RunningMate,
+		r.Party,
+		strconv.FormatInt(r.Ballots, 10),
+		strconv.FormatFloat(r.Share, 'f', 2, 64),
+		strconv.FormatBool(r.Winner),
+		strconv.FormatInt(r.Margin, 10),
+	}
+}
+
+// candidateTotal accumulates one candidate's ballots within a scope
+// (a division, or the whole country) while aggregate walks results.
+type candidateTotal struct {
+	Candidate Candidate
+	Ballots   int64
+}
+
+// aggregate groups results by Division and by candidate Number,
+// producing one SummaryRow per candidate per division plus a national
+// roll-up.
+func aggregate(results []*Result) []SummaryRow {
+	byDivision := make(map[string]map[string]*candidateTotal)
+	byDivisionOrder := make([]string, 0)
+	national := make(map[string]*candidateTotal)
+
+	for _, res := range results {
+		totals, ok := byDivision[res.Division]
+		if !ok {
+			totals = make(map[string]*candidateTotal)
+			byDivision[res.Division] = totals
+			byDivisionOrder = append(byDivisionOrder, res.Division)
+		}
+
+		if totals[res.Number] == nil {
+			totals[res.Number] = &candidateTotal{Candidate: res.Candidate}
+		}
+		totals[res.Number].Ballots += res.Ballots
+
+		if national[res.Number] == nil {
+			national[res.Number] = &candidateTotal{Candidate: res.Candidate}
+		}
+		national[res.Number].Ballots += res.Ballots
+	}
+
+	var rows []SummaryRow
+	for _, division := range byDivisionOrder {
+		rows = append(rows, summarizeScope("division", division, byDivision[division])...)
+	}
+	rows = append(rows, summarizeScope("national", "", national)...)
+	return rows
+}
+
+// summarizeScope turns one scope's candidate totals into ranked
+// SummaryRows, marking the winner and its margin over the runner-up.
+// Ranking ties (equal Ballots) break on Number so the result is
+// deterministic instead of depending on map iteration order; a tie at
+// the top isn't marked as a Winner, since nobody is actually ahead.
+func summarizeScope(scope, division string, totals map[string]*candidateTotal) []SummaryRow {
+	numbers := make([]string, 0, len(totals))
+	var sum int64
+	for number, total := range totals {
+		numbers = append(numbers, number)
+		sum += total.Ballots
+	}
+	sort.SliceStable(numbers, func(i, j int) bool {
+		a, b := totals[numbers[i]], totals[numbers[j]]
+		if a.Ballots != b.Ballots {
+			return a.Ballots > b.Ballots
+		}
+		return numberLess(numbers[i], numbers[j])
+	})
+
+	rows := make([]SummaryRow, 0, len(numbers))
+	for i, number := range numbers {
+		total := totals[number]
+
+		var share float64
+		if sum > 0 {
+			share = float64(total.Ballots) / float64(sum) * 100
+		}
+
+		var margin int64
+		winner := false
+		if i == 0 {
+			winner = len(numbers) == 1 || total.Ballots > totals[numbers[1]].Ballots
+			if len(numbers) > 1 {
+				margin = total.Ballots - totals[numbers[1]].Ballots
+			}
+		}
+
+		rows = append(rows, SummaryRow{
+			Scope:       scope,
+			Division:    division,
+			Number:      number,
+			Name:        total.Candidate.Name,
+			RunningMate: total.Candidate.RunningMate,
+			Party:       total.Candidate.Party,
+			Ballots:     total.Ballots,
+			Share:       share,
+			Winner:      winner,
+			Margin:      margin,
+		})
+	}
+	return rows
+}
+
+// numberLess orders two ballot Numbers for tiebreaking: numerically
+// when both parse as integers, lexically otherwise.
+func numberLess(a, b string) bool {
+	ai, aErr := strconv.Atoi(a)
+	bi, bErr := strconv.Atoi(b)
+	if aErr == nil && bErr == nil {
+		return ai < bi
+	}
+	return a < b
+}
+
+// runSummary implements the `summary` subcommand: load previously
+// scraped results and write their aggregation through the same sink
+// formats the crawler uses.
+func runSummary(args []string) error {
+	fs := flag.NewFlagSet("summary", flag.ExitOnError)
+	format := fs.String("format", "csv", "output format: csv, json, ndjson, or sqlite")
+	output := fs.String("output", "", "output path (default: stdout; required for sqlite)")
+	input := fs.String("input", "", "previously-scraped CSV or JSON file to re-aggregate (required)")
+	inputFormat := fs.String("input-format", "csv", "format of -input: csv, json, or ndjson")
+	fs.Parse(args)
+
+	if *input == "" {
+		return fmt.Errorf("summary: -input is required")
+	}
+
+	results, err := loadResults(*input, *inputFormat)
+	if err != nil {
+		return fmt.Errorf("summary: %w", err)
+	}
+
+	rows := aggregate(results)
+
+	return writeSummary(rows, *format, *output)
+}
+
+// loadResults reads back a previously-scraped CSV/JSON/NDJSON file, so
+// users can re-aggregate without re-crawling.
+func loadResults(path, format string) ([]*Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	switch format {
+	case "csv":
+		return loadResultsCSV(f)
+	case "json":
+		var results []*Result
+		if err := json.NewDecoder(f).Decode(&results); err != nil {
+			return nil, err
+		}
+		return results, nil
+	case "ndjson":
+		var results []*Result
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var res Result
+			if err := json.Unmarshal(scanner.Bytes(), &res); err != nil {
+				return nil, err
+			}
+			results = append(results, &res)
+		}
+		return results, scanner.Err()
+	default:
+		return nil, fmt.Errorf("unknown input format %q", format)
+	}
+}
+
+func loadResultsCSV(f *os.File) ([]*Result, error) {
+	r := csv.NewReader(f)
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	results := make([]*Result, 0, len(records)-1)
+	for _, record := range records[1:] {
+		ballots, err := strconv.ParseInt(record[6], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		percentage, err := strconv.ParseFloat(record[7], 64)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &Result{
+			Division: record[0],
+			District: record[1],
+			Number:   record[2],
+			Candidate: Candidate{
+				Name:        record[3],
+				RunningMate: record[4],
+				Party:       record[5],
+			},
+			Ballots:    ballots,
+			Percentage: percentage,
+		})
+	}
+	return results, nil
+}
+
+// writeSummary writes rows through the format named by -format, reusing
+// the same CSV/JSON/NDJSON/SQLite encodings as the crawler's sinks.
+// sqlite opens its own database handle on path directly; the other
+// formats open path as a plain file (or stdout, if path is empty).
+func writeSummary(rows []SummaryRow, format, path string) error {
+	if format == "sqlite" {
+		return writeSummarySQLite(rows, path)
+	}
+
+	w, err := openOutput(path)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "csv", "":
+		out := csv.NewWriter(w)
+		if err := out.Write(summaryHeader); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := out.Write(summaryRowToRecord(row)); err != nil {
+				return err
+			}
+		}
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return err
+		}
+		return w.Close()
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rows); err != nil {
+			return err
+		}
+		return w.Close()
+	case "ndjson":
+		enc := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return err
+			}
+		}
+		return w.Close()
+	default:
+		w.Close()
+		return fmt.Errorf("unknown sink format %q", format)
+	}
+}
+
+func writeSummarySQLite(rows []SummaryRow, path string) error {
+	if path == "" {
+		return fmt.Errorf("sqlite sink requires -output <path>")
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS summary (
+		scope        TEXT,
+		division     TEXT,
+		number       TEXT,
+		name         TEXT,
+		running_mate TEXT,
+		party        TEXT,
+		ballots      INTEGER,
+		share        REAL,
+		winner       INTEGER,
+		margin       INTEGER
+	)`); err != nil {
+		return err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(`INSERT INTO summary (scope, division, number, name, running_mate, party, ballots, share, winner, margin)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		if _, err := stmt.Exec(
+			row.Scope, row.Division, row.Number, row.Name, row.RunningMate, row.Party,
+			row.Ballots, row.Share, row.Winner, row.Margin,
+		); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestSummarizeScopeDeterministicOnTie(t *testing.T) {
+	totals := map[string]*candidateTotal{
+		"1": {Candidate: Candidate{Name: "A"}, Ballots: 100},
+		"2": {Candidate: Candidate{Name: "B"}, Ballots: 100},
+		"3": {Candidate: Candidate{Name: "C"}, Ballots: 50},
+	}
+
+	for i := 0; i < 5; i++ {
+		rows := summarizeScope("national", "", totals)
+
+		if rows[0].Number != "1" || rows[1].Number != "2" || rows[2].Number != "3" {
+			t.Fatalf("run %d: order = [%s %s %s], want [1 2 3]", i, rows[0].Number, rows[1].Number, rows[2].Number)
+		}
+		if rows[0].Winner {
+			t.Fatalf("run %d: tied leader %q marked Winner", i, rows[0].Number)
+		}
+		for _, row := range rows {
+			if row.Winner {
+				t.Fatalf("run %d: no candidate should be Winner on a full tie, got %q", i, row.Number)
+			}
+		}
+	}
+}
+
+func TestSummarizeScopeWinnerAndMargin(t *testing.T) {
+	totals := map[string]*candidateTotal{
+		"1": {Candidate: Candidate{Name: "A"}, Ballots: 300},
+		"2": {Candidate: Candidate{Name: "B"}, Ballots: 200},
+	}
+
+	rows := summarizeScope("national", "", totals)
+
+	if !rows[0].Winner {
+		t.Fatalf("leading candidate %q not marked Winner", rows[0].Number)
+	}
+	if rows[0].Margin != 100 {
+		t.Errorf("Margin = %d, want 100", rows[0].Margin)
+	}
+	if rows[1].Winner {
+		t.Errorf("runner-up %q incorrectly marked Winner", rows[1].Number)
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	results := []*Result{
+		{Division: "台北市", Number: "1", Candidate: Candidate{Name: "A"}, Ballots: 10},
+		{Division: "台北市", Number: "2", Candidate: Candidate{Name: "B"}, Ballots: 20},
+		{Division: "高雄市", Number: "1", Candidate: Candidate{Name: "A"}, Ballots: 30},
+		{Division: "高雄市", Number: "2", Candidate: Candidate{Name: "B"}, Ballots: 5},
+	}
+
+	rows := aggregate(results)
+
+	var national []SummaryRow
+	for _, row := range rows {
+		if row.Scope == "national" {
+			national = append(national, row)
+		}
+	}
+
+	if len(national) != 2 {
+		t.Fatalf("got %d national rows, want 2", len(national))
+	}
+	if national[0].Number != "1" || national[0].Ballots != 40 {
+		t.Errorf("national winner = %+v, want Number=1 Ballots=40", national[0])
+	}
+	if national[1].Number != "2" || national[1].Ballots != 25 {
+		t.Errorf("national runner-up = %+v, want Number=2 Ballots=25", national[1])
+	}
+}